@@ -0,0 +1,208 @@
+package rest
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AccessEntry captures the metadata of a single request/response cycle
+type AccessEntry struct {
+	Method        string
+	Host          string
+	Path          string
+	Query         string
+	Remote        string
+	UserAgent     string
+	Referer       string
+	RequestID     string
+	RequestBytes  int
+	ResponseBytes int
+	Status        int
+	Duration      time.Duration
+	TLSVersion    string
+	TLSCipher     string
+}
+
+// AccessLogger receives one AccessEntry per request handled by SetupRouter's
+// wrapper. *Logging implements it, so it is the default; callers can plug
+// in their own through REST.SetAccessLogger.
+type AccessLogger interface {
+	Log(entry AccessEntry)
+}
+
+// AccessLogEncoder renders an AccessEntry as a single log line
+type AccessLogEncoder interface {
+	Encode(entry AccessEntry) string
+}
+
+// JSONEncoder renders the entry as a JSON object
+type JSONEncoder struct{}
+
+// Encode implements AccessLogEncoder
+func (JSONEncoder) Encode(entry AccessEntry) string {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q}`, err.Error())
+	}
+	return string(b)
+}
+
+// LogfmtEncoder renders the entry as space-separated key=value pairs
+type LogfmtEncoder struct{}
+
+// Encode implements AccessLogEncoder
+func (LogfmtEncoder) Encode(entry AccessEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "method=%s host=%s path=%s query=%q remote=%s status=%d size=%d duration=%s",
+		entry.Method, entry.Host, entry.Path, entry.Query, entry.Remote, entry.Status, entry.ResponseBytes, entry.Duration)
+	if entry.RequestID != "" {
+		fmt.Fprintf(&b, " request_id=%s", entry.RequestID)
+	}
+	if entry.UserAgent != "" {
+		fmt.Fprintf(&b, " user_agent=%q", entry.UserAgent)
+	}
+	if entry.Referer != "" {
+		fmt.Fprintf(&b, " referer=%q", entry.Referer)
+	}
+	if entry.TLSVersion != "" {
+		fmt.Fprintf(&b, " tls_version=%s tls_cipher=%s", entry.TLSVersion, entry.TLSCipher)
+	}
+	return b.String()
+}
+
+// CommonLogEncoder renders the entry using the NCSA combined log format
+type CommonLogEncoder struct{}
+
+// Encode implements AccessLogEncoder
+func (CommonLogEncoder) Encode(entry AccessEntry) string {
+	uri := entry.Path
+	if entry.Query != "" {
+		uri += "?" + entry.Query
+	}
+	return fmt.Sprintf(`%s - - [%s] "%s %s HTTP/1.1" %d %d %q %q`,
+		entry.Remote,
+		time.Now().Format("02/Jan/2006:15:04:05 -0700"),
+		entry.Method, uri, entry.Status, entry.ResponseBytes,
+		entry.Referer, entry.UserAgent,
+	)
+}
+
+// FieldPolicy transforms a single AccessEntry field value before it is logged
+type FieldPolicy func(value string) string
+
+// Keep returns the value unchanged
+func Keep(value string) string {
+	return value
+}
+
+// Drop discards the value
+func Drop(value string) string {
+	return ""
+}
+
+// Hash returns a FieldPolicy that replaces the value with its salted SHA-256
+// digest, useful to log client IPs without storing them in the clear
+func Hash(salt string) FieldPolicy {
+	return func(value string) string {
+		sum := sha256.Sum256([]byte(salt + value))
+		return hex.EncodeToString(sum[:])
+	}
+}
+
+// Truncate returns a FieldPolicy that keeps at most n bytes of the value
+func Truncate(n int) FieldPolicy {
+	return func(value string) string {
+		if len(value) <= n {
+			return value
+		}
+		return value[:n]
+	}
+}
+
+// FieldFilter applies a FieldPolicy to selected AccessEntry fields before
+// they reach an AccessLogger, so operators can redact query strings or hash
+// client IPs to meet data-protection requirements. A nil policy keeps the
+// field unchanged.
+type FieldFilter struct {
+	Query     FieldPolicy
+	Remote    FieldPolicy
+	UserAgent FieldPolicy
+	Referer   FieldPolicy
+}
+
+func (f FieldFilter) apply(entry AccessEntry) AccessEntry {
+	if f.Query != nil {
+		entry.Query = f.Query(entry.Query)
+	}
+	if f.Remote != nil {
+		entry.Remote = f.Remote(entry.Remote)
+	}
+	if f.UserAgent != nil {
+		entry.UserAgent = f.UserAgent(entry.UserAgent)
+	}
+	if f.Referer != nil {
+		entry.Referer = f.Referer(entry.Referer)
+	}
+	return entry
+}
+
+// Log implements AccessLogger, applying l's field filter and encoding the
+// entry with l's encoder before writing it at info level
+func (l *Logging) Log(entry AccessEntry) {
+	entry = l.fieldFilter.apply(entry)
+	l.logger.Info(l.accessEncoder.Encode(entry))
+}
+
+// emitAccessLog builds an AccessEntry out of r and its outcome, and hands it
+// to rr.accessLogger
+func (rr *REST) emitAccessLog(r *http.Request, requestID string, status, size int, duration time.Duration) {
+
+	requestBytes := 0
+	if r.ContentLength > 0 {
+		requestBytes = int(r.ContentLength)
+	}
+
+	entry := AccessEntry{
+		Method:        r.Method,
+		Host:          r.Host,
+		Path:          r.URL.Path,
+		Query:         r.URL.RawQuery,
+		Remote:        r.RemoteAddr,
+		UserAgent:     r.UserAgent(),
+		Referer:       r.Referer(),
+		RequestID:     requestID,
+		RequestBytes:  requestBytes,
+		ResponseBytes: size,
+		Status:        status,
+		Duration:      duration,
+	}
+
+	if r.TLS != nil {
+		entry.TLSVersion = tlsVersionName(r.TLS.Version)
+		entry.TLSCipher = tls.CipherSuiteName(r.TLS.CipherSuite)
+	}
+
+	rr.accessLogger.Log(entry)
+
+}
+
+// tlsVersionName renders a tls.VersionTLSxx constant as a human string
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	default:
+		return "unknown"
+	}
+}
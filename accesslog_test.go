@@ -0,0 +1,175 @@
+package rest
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONEncoderEncode(t *testing.T) {
+	entry := AccessEntry{Method: "GET", Path: "/foo", Status: 200, ResponseBytes: 42}
+
+	out := JSONEncoder{}.Encode(entry)
+
+	for _, want := range []string{`"Method":"GET"`, `"Path":"/foo"`, `"Status":200`, `"ResponseBytes":42`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("JSON output %q does not contain %q", out, want)
+		}
+	}
+}
+
+func TestLogfmtEncoderEncodeOmitsEmptyOptionalFields(t *testing.T) {
+	entry := AccessEntry{
+		Method: "GET", Host: "example.com", Path: "/foo", Query: "a=1",
+		Remote: "127.0.0.1", Status: 200, ResponseBytes: 42, Duration: time.Second,
+	}
+
+	out := LogfmtEncoder{}.Encode(entry)
+
+	if !strings.Contains(out, `method=GET host=example.com path=/foo query="a=1" remote=127.0.0.1 status=200 size=42`) {
+		t.Fatalf("unexpected logfmt output: %q", out)
+	}
+	for _, absent := range []string{"request_id=", "user_agent=", "referer=", "tls_version="} {
+		if strings.Contains(out, absent) {
+			t.Fatalf("logfmt output %q should not contain %q when the field is empty", out, absent)
+		}
+	}
+}
+
+func TestLogfmtEncoderEncodeIncludesOptionalFieldsWhenSet(t *testing.T) {
+	entry := AccessEntry{
+		RequestID: "req-1", UserAgent: "curl/8", Referer: "http://ref",
+		TLSVersion: "TLS 1.3", TLSCipher: "TLS_AES_128_GCM_SHA256",
+	}
+
+	out := LogfmtEncoder{}.Encode(entry)
+
+	for _, want := range []string{
+		"request_id=req-1",
+		`user_agent="curl/8"`,
+		`referer="http://ref"`,
+		"tls_version=TLS 1.3 tls_cipher=TLS_AES_128_GCM_SHA256",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("logfmt output %q does not contain %q", out, want)
+		}
+	}
+}
+
+func TestCommonLogEncoderEncode(t *testing.T) {
+	entry := AccessEntry{
+		Remote: "127.0.0.1", Method: "GET", Path: "/foo", Query: "a=1",
+		Status: 200, ResponseBytes: 42, Referer: "http://ref", UserAgent: "curl/8",
+	}
+
+	out := CommonLogEncoder{}.Encode(entry)
+
+	if !strings.HasPrefix(out, `127.0.0.1 - - [`) {
+		t.Fatalf("expected common log output to start with the remote address, got %q", out)
+	}
+	if !strings.Contains(out, `"GET /foo?a=1 HTTP/1.1" 200 42 "http://ref" "curl/8"`) {
+		t.Fatalf("unexpected common log output: %q", out)
+	}
+}
+
+func TestFieldFilterApplyDispatchesConfiguredPolicies(t *testing.T) {
+	filter := FieldFilter{
+		Query:     Drop,
+		Remote:    Keep,
+		UserAgent: Truncate(4),
+		Referer:   Hash("salt"),
+	}
+	entry := AccessEntry{Query: "secret=1", Remote: "127.0.0.1", UserAgent: "curl/8.0", Referer: "http://example.com"}
+
+	out := filter.apply(entry)
+
+	if out.Query != "" {
+		t.Fatalf("Query = %q, want dropped (empty)", out.Query)
+	}
+	if out.Remote != "127.0.0.1" {
+		t.Fatalf("Remote = %q, want unchanged by Keep", out.Remote)
+	}
+	if out.UserAgent != "curl" {
+		t.Fatalf("UserAgent = %q, want truncated to 4 bytes", out.UserAgent)
+	}
+
+	sum := sha256.Sum256([]byte("salt" + "http://example.com"))
+	if want := hex.EncodeToString(sum[:]); out.Referer != want {
+		t.Fatalf("Referer = %q, want salted hash %q", out.Referer, want)
+	}
+}
+
+func TestFieldFilterApplyLeavesUnconfiguredFieldsUnchanged(t *testing.T) {
+	entry := AccessEntry{Query: "a=1", Remote: "127.0.0.1", UserAgent: "curl/8", Referer: "http://ref"}
+
+	out := FieldFilter{}.apply(entry)
+
+	if out != entry {
+		t.Fatalf("apply() with no policies configured = %+v, want unchanged %+v", out, entry)
+	}
+}
+
+func TestTruncateBoundaries(t *testing.T) {
+	policy := Truncate(4)
+
+	if got := policy("abc"); got != "abc" {
+		t.Fatalf("Truncate(4)(%q) = %q, want unchanged value shorter than n", "abc", got)
+	}
+	if got := policy("abcd"); got != "abcd" {
+		t.Fatalf("Truncate(4)(%q) = %q, want unchanged value exactly n bytes", "abcd", got)
+	}
+	if got := policy("abcde"); got != "abcd" {
+		t.Fatalf("Truncate(4)(%q) = %q, want truncated to 4 bytes", "abcde", got)
+	}
+}
+
+type fakeAccessLogger struct {
+	entry AccessEntry
+}
+
+func (f *fakeAccessLogger) Log(entry AccessEntry) {
+	f.entry = entry
+}
+
+func TestEmitAccessLogPopulatesRequestAndResponseBytes(t *testing.T) {
+	rr := &REST{}
+	logger := &fakeAccessLogger{}
+	rr.accessLogger = logger
+
+	r := httptest.NewRequest("POST", "/foo", nil)
+	r.ContentLength = 123
+
+	rr.emitAccessLog(r, "req-1", 200, 456, time.Second)
+
+	if logger.entry.RequestBytes != 123 {
+		t.Fatalf("RequestBytes = %d, want 123", logger.entry.RequestBytes)
+	}
+	if logger.entry.ResponseBytes != 456 {
+		t.Fatalf("ResponseBytes = %d, want 456", logger.entry.ResponseBytes)
+	}
+	if logger.entry.TLSVersion != "" || logger.entry.TLSCipher != "" {
+		t.Fatalf("expected no TLS fields on a plaintext request, got version=%q cipher=%q", logger.entry.TLSVersion, logger.entry.TLSCipher)
+	}
+}
+
+func TestEmitAccessLogPopulatesTLSFields(t *testing.T) {
+	rr := &REST{}
+	logger := &fakeAccessLogger{}
+	rr.accessLogger = logger
+
+	r := httptest.NewRequest("GET", "/foo", nil)
+	r.TLS = &tls.ConnectionState{Version: tls.VersionTLS13, CipherSuite: tls.TLS_AES_128_GCM_SHA256}
+
+	rr.emitAccessLog(r, "req-1", 200, 0, 0)
+
+	if logger.entry.TLSVersion != "TLS 1.3" {
+		t.Fatalf("TLSVersion = %q, want TLS 1.3", logger.entry.TLSVersion)
+	}
+	if logger.entry.TLSCipher != tls.CipherSuiteName(tls.TLS_AES_128_GCM_SHA256) {
+		t.Fatalf("TLSCipher = %q, want %q", logger.entry.TLSCipher, tls.CipherSuiteName(tls.TLS_AES_128_GCM_SHA256))
+	}
+}
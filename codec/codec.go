@@ -0,0 +1,16 @@
+// Package codec provides the Encoder/Decoder implementations used by
+// rest.Negotiate and rest.GetFromBody for content negotiation.
+package codec
+
+// Encoder writes a single value in a specific wire format. Callers must
+// call Close after Encode to flush any buffered output; formats that
+// write directly to the underlying writer implement Close as a no-op.
+type Encoder interface {
+	Encode(v interface{}) error
+	Close() error
+}
+
+// Decoder reads a single value in a specific wire format
+type Decoder interface {
+	Decode(v interface{}) error
+}
@@ -0,0 +1,115 @@
+package codec
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type codecTestValue struct {
+	Name string `json:"name" msgpack:"name" yaml:"name"`
+	Age  int    `json:"age" msgpack:"age" yaml:"age"`
+}
+
+func TestJSONEncoderDecoderRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	in := codecTestValue{Name: "ada", Age: 36}
+
+	enc := NewJSONEncoder(&buf)
+	if err := enc.Encode(in); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	var out codecTestValue
+	if err := NewJSONDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if out != in {
+		t.Fatalf("round trip = %+v, want %+v", out, in)
+	}
+}
+
+func TestMessagePackEncoderDecoderRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	in := codecTestValue{Name: "grace", Age: 42}
+
+	enc := NewMessagePackEncoder(&buf)
+	if err := enc.Encode(in); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	var out codecTestValue
+	if err := NewMessagePackDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if out != in {
+		t.Fatalf("round trip = %+v, want %+v", out, in)
+	}
+}
+
+func TestYAMLEncoderDecoderRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	in := codecTestValue{Name: "alan", Age: 41}
+
+	enc := NewYAMLEncoder(&buf)
+	if err := enc.Encode(in); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	var out codecTestValue
+	if err := NewYAMLDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if out != in {
+		t.Fatalf("round trip = %+v, want %+v", out, in)
+	}
+}
+
+func TestProtobufEncoderDecoderRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	in := wrapperspb.String("hopper")
+
+	enc := NewProtobufEncoder(&buf)
+	if err := enc.Encode(in); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	out := &wrapperspb.StringValue{}
+	if err := NewProtobufDecoder(&buf).Decode(out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if out.Value != in.Value {
+		t.Fatalf("round trip = %q, want %q", out.Value, in.Value)
+	}
+}
+
+func TestProtobufEncoderRejectsNonProtoMessage(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := NewProtobufEncoder(&buf).Encode(codecTestValue{Name: "not-proto"})
+	if err == nil {
+		t.Fatal("expected an error encoding a value that does not implement proto.Message")
+	}
+}
+
+func TestProtobufDecoderRejectsNonProtoMessage(t *testing.T) {
+	buf := bytes.NewBufferString("")
+
+	var out codecTestValue
+	if err := NewProtobufDecoder(buf).Decode(&out); err == nil {
+		t.Fatal("expected an error decoding into a value that does not implement proto.Message")
+	}
+}
@@ -0,0 +1,45 @@
+package codec
+
+import (
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+var jsonAPI = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// JSONEncoder encodes values as JSON
+type JSONEncoder struct {
+	enc *jsoniter.Encoder
+}
+
+// NewJSONEncoder returns an Encoder that writes JSON to w
+func NewJSONEncoder(w io.Writer) Encoder {
+	return &JSONEncoder{enc: jsonAPI.NewEncoder(w)}
+}
+
+// Encode implements Encoder
+func (e *JSONEncoder) Encode(v interface{}) error {
+	return e.enc.Encode(v)
+}
+
+// Close implements Encoder. JSONEncoder writes directly to the underlying
+// writer, so there is nothing to flush.
+func (e *JSONEncoder) Close() error {
+	return nil
+}
+
+// JSONDecoder decodes JSON values
+type JSONDecoder struct {
+	dec *jsoniter.Decoder
+}
+
+// NewJSONDecoder returns a Decoder that reads JSON from r
+func NewJSONDecoder(r io.Reader) Decoder {
+	return &JSONDecoder{dec: jsonAPI.NewDecoder(r)}
+}
+
+// Decode implements Decoder
+func (d *JSONDecoder) Decode(v interface{}) error {
+	return d.dec.Decode(v)
+}
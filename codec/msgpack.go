@@ -0,0 +1,43 @@
+package codec
+
+import (
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// MessagePackEncoder encodes values as MessagePack
+type MessagePackEncoder struct {
+	enc *msgpack.Encoder
+}
+
+// NewMessagePackEncoder returns an Encoder that writes MessagePack to w
+func NewMessagePackEncoder(w io.Writer) Encoder {
+	return &MessagePackEncoder{enc: msgpack.NewEncoder(w)}
+}
+
+// Encode implements Encoder
+func (e *MessagePackEncoder) Encode(v interface{}) error {
+	return e.enc.Encode(v)
+}
+
+// Close implements Encoder. MessagePackEncoder writes directly to the
+// underlying writer, so there is nothing to flush.
+func (e *MessagePackEncoder) Close() error {
+	return nil
+}
+
+// MessagePackDecoder decodes MessagePack values
+type MessagePackDecoder struct {
+	dec *msgpack.Decoder
+}
+
+// NewMessagePackDecoder returns a Decoder that reads MessagePack from r
+func NewMessagePackDecoder(r io.Reader) Decoder {
+	return &MessagePackDecoder{dec: msgpack.NewDecoder(r)}
+}
+
+// Decode implements Decoder
+func (d *MessagePackDecoder) Decode(v interface{}) error {
+	return d.dec.Decode(v)
+}
@@ -0,0 +1,62 @@
+package codec
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtobufEncoder encodes proto.Message values as binary protobuf
+type ProtobufEncoder struct {
+	w io.Writer
+}
+
+// NewProtobufEncoder returns an Encoder that writes protobuf-encoded bytes to w
+func NewProtobufEncoder(w io.Writer) Encoder {
+	return &ProtobufEncoder{w: w}
+}
+
+// Encode implements Encoder. v must implement proto.Message.
+func (e *ProtobufEncoder) Encode(v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("codec: %T does not implement proto.Message", v)
+	}
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(b)
+	return err
+}
+
+// Close implements Encoder. ProtobufEncoder writes directly to the
+// underlying writer, so there is nothing to flush.
+func (e *ProtobufEncoder) Close() error {
+	return nil
+}
+
+// ProtobufDecoder decodes binary protobuf into proto.Message values
+type ProtobufDecoder struct {
+	r io.Reader
+}
+
+// NewProtobufDecoder returns a Decoder that reads protobuf-encoded bytes from r
+func NewProtobufDecoder(r io.Reader) Decoder {
+	return &ProtobufDecoder{r: r}
+}
+
+// Decode implements Decoder. v must implement proto.Message.
+func (d *ProtobufDecoder) Decode(v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("codec: %T does not implement proto.Message", v)
+	}
+	b, err := ioutil.ReadAll(d.r)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(b, msg)
+}
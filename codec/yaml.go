@@ -0,0 +1,44 @@
+package codec
+
+import (
+	"io"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// YAMLEncoder encodes values as YAML
+type YAMLEncoder struct {
+	enc *yaml.Encoder
+}
+
+// NewYAMLEncoder returns an Encoder that writes YAML to w
+func NewYAMLEncoder(w io.Writer) Encoder {
+	return &YAMLEncoder{enc: yaml.NewEncoder(w)}
+}
+
+// Encode implements Encoder
+func (e *YAMLEncoder) Encode(v interface{}) error {
+	return e.enc.Encode(v)
+}
+
+// Close implements Encoder. yaml.Encoder buffers the document in its
+// emitter and only flushes it to the underlying writer on Close, so
+// callers must call this after Encode to get any output at all.
+func (e *YAMLEncoder) Close() error {
+	return e.enc.Close()
+}
+
+// YAMLDecoder decodes YAML values
+type YAMLDecoder struct {
+	dec *yaml.Decoder
+}
+
+// NewYAMLDecoder returns a Decoder that reads YAML from r
+func NewYAMLDecoder(r io.Reader) Decoder {
+	return &YAMLDecoder{dec: yaml.NewDecoder(r)}
+}
+
+// Decode implements Decoder
+func (d *YAMLDecoder) Decode(v interface{}) error {
+	return d.dec.Decode(v)
+}
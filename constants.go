@@ -23,17 +23,25 @@ type HTTPErrorStatus struct {
 	Message          string            `json:"message"`
 	Reason           string            `json:"reason,omitempty"`
 	ValidationErrors map[string]string `json:"validation_errors,omitempty"`
+	RequestID        string            `json:"request_id,omitempty"`
 }
 
 // APIEndpoint is the struct that holds the Handler and Matchers (if any) to build the router
 type APIEndpoint struct {
-	Handler APIHandler
-	Matcher APIMatcher
+	Handler     APIHandler
+	Matcher     APIMatcher
+	Middlewares []Middleware
 }
 
 // APIHandler defines the handler function that will be used for each API endpoint
 type APIHandler func(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 
+// Middleware wraps an APIHandler to add behaviour that runs before and/or
+// after it, such as logging, authentication or recovery from panics.
+// Middlewares are composed once, at router setup time, around each
+// endpoint's Handler
+type Middleware func(APIHandler) APIHandler
+
 // APIMatcher is an array of rules that must be applied for each request to ensure
 //   the required input is being passed to the API
 type APIMatcher []string
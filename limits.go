@@ -0,0 +1,122 @@
+package rest
+
+import (
+	"net/http"
+	"regexp"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+// limitStats holds the atomic counters backing REST.Stats
+type limitStats struct {
+	inFlightCurrent          int64
+	inFlightRejectedTotal    int64
+	longRunningCurrent       int64
+	longRunningRejectedTotal int64
+}
+
+// Stats is a point-in-time snapshot of the in-flight request limiter
+type Stats struct {
+	InFlightCurrent          int64
+	InFlightRejectedTotal    int64
+	LongRunningCurrent       int64
+	LongRunningRejectedTotal int64
+}
+
+// SetLimits configures in-flight request throttling. maxInFlight caps the
+// number of concurrent short requests; requests whose "METHOD path" matches
+// longRunningRE are classified as long-running and instead count against
+// maxLongRunning, a separate budget. A limit of 0 disables throttling for
+// that class. It must be called before SetupRouter.
+func (rr *REST) SetLimits(maxInFlight, maxLongRunning int, longRunningRE string) error {
+
+	if longRunningRE != "" {
+		re, err := regexp.Compile(longRunningRE)
+		if err != nil {
+			return err
+		}
+		rr.longRunningRE = re
+	}
+
+	rr.maxInFlight = maxInFlight
+	if maxInFlight > 0 {
+		rr.inFlightSem = make(chan struct{}, maxInFlight)
+	}
+
+	rr.maxLongRunning = maxLongRunning
+	if maxLongRunning > 0 {
+		rr.longRunningSem = make(chan struct{}, maxLongRunning)
+	}
+
+	return nil
+
+}
+
+// Stats returns a snapshot of the current in-flight request counters
+func (rr *REST) Stats() Stats {
+	return Stats{
+		InFlightCurrent:          atomic.LoadInt64(&rr.stats.inFlightCurrent),
+		InFlightRejectedTotal:    atomic.LoadInt64(&rr.stats.inFlightRejectedTotal),
+		LongRunningCurrent:       atomic.LoadInt64(&rr.stats.longRunningCurrent),
+		LongRunningRejectedTotal: atomic.LoadInt64(&rr.stats.longRunningRejectedTotal),
+	}
+}
+
+// isLongRunning reports whether method+path was classified as long-running
+func (rr *REST) isLongRunning(method, path string) bool {
+	return rr.longRunningRE != nil && rr.longRunningRE.MatchString(method+" "+path)
+}
+
+// acquire reserves a slot in the semaphore matching method+path's class. It
+// returns a release func to be called (deferred) once the request completes,
+// and whether the request was rejected because its class is saturated.
+func (rr *REST) acquire(method, path string) (release func(), rejected bool) {
+
+	if rr.isLongRunning(method, path) {
+		if rr.longRunningSem == nil {
+			return func() {}, false
+		}
+		select {
+		case rr.longRunningSem <- struct{}{}:
+			atomic.AddInt64(&rr.stats.longRunningCurrent, 1)
+			return func() {
+				<-rr.longRunningSem
+				atomic.AddInt64(&rr.stats.longRunningCurrent, -1)
+			}, false
+		default:
+			atomic.AddInt64(&rr.stats.longRunningRejectedTotal, 1)
+			return nil, true
+		}
+	}
+
+	if rr.inFlightSem == nil {
+		return func() {}, false
+	}
+
+	select {
+	case rr.inFlightSem <- struct{}{}:
+		atomic.AddInt64(&rr.stats.inFlightCurrent, 1)
+		return func() {
+			<-rr.inFlightSem
+			atomic.AddInt64(&rr.stats.inFlightCurrent, -1)
+		}, false
+	default:
+		atomic.AddInt64(&rr.stats.inFlightRejectedTotal, 1)
+		return nil, true
+	}
+
+}
+
+// rejectTooManyRequests writes a 429 response with a Retry-After header and
+// logs a warning, used when the semaphore of the request's class (short or
+// long-running) is saturated
+func (rr *REST) rejectTooManyRequests(w http.ResponseWriter, r *http.Request, method, route string) {
+	w.Header().Set("Retry-After", "1")
+	rr.logger.With(r.Context()).Warn("in-flight request limit reached",
+		zap.String("method", method),
+		zap.String("route", route),
+		zap.String("remote", r.RemoteAddr),
+	)
+	ErrorResponse(w, r, http.StatusTooManyRequests, "too many in-flight requests")
+}
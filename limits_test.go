@@ -0,0 +1,76 @@
+package rest
+
+import "testing"
+
+func TestAcquireInFlightSaturationAndRelease(t *testing.T) {
+	rr := &REST{}
+	if err := rr.SetLimits(1, 0, ""); err != nil {
+		t.Fatalf("SetLimits: %v", err)
+	}
+
+	release, rejected := rr.acquire("GET", "/foo")
+	if rejected {
+		t.Fatal("expected the first request to be admitted")
+	}
+	if got := rr.Stats().InFlightCurrent; got != 1 {
+		t.Fatalf("InFlightCurrent = %d, want 1", got)
+	}
+
+	if _, rejected := rr.acquire("GET", "/foo"); !rejected {
+		t.Fatal("expected a second concurrent request to be rejected once the limit is saturated")
+	}
+	if got := rr.Stats().InFlightRejectedTotal; got != 1 {
+		t.Fatalf("InFlightRejectedTotal = %d, want 1", got)
+	}
+
+	release()
+
+	if got := rr.Stats().InFlightCurrent; got != 0 {
+		t.Fatalf("InFlightCurrent after release = %d, want 0", got)
+	}
+	if _, rejected := rr.acquire("GET", "/foo"); rejected {
+		t.Fatal("expected the slot freed by release to admit a new request")
+	}
+}
+
+func TestAcquireLongRunningClassification(t *testing.T) {
+	rr := &REST{}
+	if err := rr.SetLimits(0, 1, "^GET /export"); err != nil {
+		t.Fatalf("SetLimits: %v", err)
+	}
+
+	release, rejected := rr.acquire("GET", "/export")
+	if rejected {
+		t.Fatal("expected the long-running request to be admitted")
+	}
+	if got := rr.Stats().LongRunningCurrent; got != 1 {
+		t.Fatalf("LongRunningCurrent = %d, want 1", got)
+	}
+
+	if _, rejected := rr.acquire("GET", "/other"); rejected {
+		t.Fatal("expected a non-matching request to bypass the long-running limiter entirely")
+	}
+
+	if _, rejected := rr.acquire("GET", "/export"); !rejected {
+		t.Fatal("expected a second long-running request to be rejected once its budget is saturated")
+	}
+	if got := rr.Stats().LongRunningRejectedTotal; got != 1 {
+		t.Fatalf("LongRunningRejectedTotal = %d, want 1", got)
+	}
+
+	release()
+
+	if got := rr.Stats().LongRunningCurrent; got != 0 {
+		t.Fatalf("LongRunningCurrent after release = %d, want 0", got)
+	}
+}
+
+func TestAcquireUnlimitedWhenNoLimitsConfigured(t *testing.T) {
+	rr := &REST{}
+
+	for i := 0; i < 100; i++ {
+		if _, rejected := rr.acquire("GET", "/foo"); rejected {
+			t.Fatalf("expected request %d to be admitted with no limits configured", i)
+		}
+	}
+}
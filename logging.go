@@ -1,6 +1,8 @@
 package rest
 
 import (
+	"context"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -9,11 +11,32 @@ import (
 // other libraries that need to log something will use this
 // to have just one way to do the things
 type Logging struct {
-	logger *zap.Logger
+	logger        *zap.Logger
+	accessEncoder AccessLogEncoder
+	fieldFilter   FieldFilter
+}
+
+// LoggingOption configures optional behaviour of a Logging instance built by NewLogging
+type LoggingOption func(*Logging)
+
+// WithAccessLogEncoder selects the encoder used to render AccessEntry values
+// logged through Logging.Log. Defaults to JSONEncoder.
+func WithAccessLogEncoder(encoder AccessLogEncoder) LoggingOption {
+	return func(l *Logging) {
+		l.accessEncoder = encoder
+	}
+}
+
+// WithFieldFilter installs a FieldFilter applied to every AccessEntry before
+// it is encoded, so operators can redact or hash sensitive fields
+func WithFieldFilter(filter FieldFilter) LoggingOption {
+	return func(l *Logging) {
+		l.fieldFilter = filter
+	}
 }
 
 // NewLogging returns a loggin instance, if no output paths passed it will default to stderr
-func NewLogging(outputPaths, errorOutputPaths []string, debug bool) (*Logging, error) {
+func NewLogging(outputPaths, errorOutputPaths []string, debug bool, opts ...LoggingOption) (*Logging, error) {
 
 	var (
 		l           Logging
@@ -22,6 +45,12 @@ func NewLogging(outputPaths, errorOutputPaths []string, debug bool) (*Logging, e
 		err         error
 	)
 
+	l.accessEncoder = JSONEncoder{}
+
+	for _, opt := range opts {
+		opt(&l)
+	}
+
 	if len(outputPaths) == 0 {
 		outputPaths = []string{"stderr"}
 	}
@@ -74,6 +103,16 @@ func (l *Logging) Logger() *zap.Logger {
 	return l.logger
 }
 
+// With returns the raw logger pre-tagged with the request_id carried by ctx,
+// so every log line emitted from it can be correlated across services. When
+// ctx does not carry a request ID, the plain logger is returned unchanged.
+func (l *Logging) With(ctx context.Context) *zap.Logger {
+	if id, ok := RequestIDFromContext(ctx); ok {
+		return l.logger.With(zap.String("request_id", id))
+	}
+	return l.logger
+}
+
 // Error writes an error trace
 func (l *Logging) Error(source, msg string, fields ...zap.Field) {
 	fields = append(fields, zap.String("source", source))
@@ -97,3 +136,11 @@ func (l *Logging) Debug(source, msg string, fields ...zap.Field) {
 		fields...,
 	)
 }
+
+// Warn writes a warning trace
+func (l *Logging) Warn(source, msg string, fields ...zap.Field) {
+	fields = append(fields, zap.String("source", source))
+	l.logger.Warn(msg,
+		fields...,
+	)
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/fernandezvara/rest"
+	"github.com/julienschmidt/httprouter"
+)
+
+// BasicAuth rejects requests that do not present the given HTTP Basic
+// credentials
+func BasicAuth(username, password string) rest.Middleware {
+	return func(next rest.APIHandler) rest.APIHandler {
+		return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+			user, pass, ok := r.BasicAuth()
+			if !ok ||
+				subtle.ConstantTimeCompare([]byte(user), []byte(username)) != 1 ||
+				subtle.ConstantTimeCompare([]byte(pass), []byte(password)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Basic realm="restricted"`)
+				rest.Unauthorized(w, r)
+				return
+			}
+
+			next(w, r, ps)
+
+		}
+	}
+}
+
+// BearerAuth rejects requests that do not present the given bearer token in
+// the Authorization header
+func BearerAuth(token string) rest.Middleware {
+	return func(next rest.APIHandler) rest.APIHandler {
+		return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+			const prefix = "Bearer "
+
+			header := r.Header.Get("Authorization")
+			if !strings.HasPrefix(header, prefix) ||
+				subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(header, prefix)), []byte(token)) != 1 {
+				rest.Unauthorized(w, r)
+				return
+			}
+
+			next(w, r, ps)
+
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestBasicAuthRejectsMissingCredentials(t *testing.T) {
+	handler := BasicAuth("alice", "s3cret")(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		t.Fatal("handler should not run without credentials")
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+	handler(w, r, nil)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if got := w.Header().Get("WWW-Authenticate"); got == "" {
+		t.Fatal("expected a WWW-Authenticate challenge header")
+	}
+}
+
+func TestBasicAuthRejectsWrongCredentials(t *testing.T) {
+	handler := BasicAuth("alice", "s3cret")(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		t.Fatal("handler should not run with the wrong credentials")
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+	r.SetBasicAuth("alice", "wrong")
+
+	handler(w, r, nil)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestBasicAuthAcceptsCorrectCredentials(t *testing.T) {
+	called := false
+	handler := BasicAuth("alice", "s3cret")(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		called = true
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+	r.SetBasicAuth("alice", "s3cret")
+
+	handler(w, r, nil)
+
+	if !called {
+		t.Fatal("expected the wrapped handler to run with correct credentials")
+	}
+}
+
+func TestBearerAuthRejectsMissingOrGarbledHeader(t *testing.T) {
+	handler := BearerAuth("tok123")(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		t.Fatal("handler should not run without a valid bearer token")
+	})
+
+	for _, header := range []string{"", "Bearer", "Basic tok123", "Bearer wrongtoken"} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+		if header != "" {
+			r.Header.Set("Authorization", header)
+		}
+
+		handler(w, r, nil)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("Authorization = %q: status = %d, want %d", header, w.Code, http.StatusUnauthorized)
+		}
+	}
+}
+
+func TestBearerAuthAcceptsCorrectToken(t *testing.T) {
+	called := false
+	handler := BearerAuth("tok123")(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		called = true
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+	r.Header.Set("Authorization", "Bearer tok123")
+
+	handler(w, r, nil)
+
+	if !called {
+		t.Fatal("expected the wrapped handler to run with the correct bearer token")
+	}
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestCORSEchoesAllowListedOriginOnly(t *testing.T) {
+	handler := CORS(CORSConfig{AllowedOrigins: []string{"https://allowed.example"}})(
+		func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {},
+	)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+	r.Header.Set("Origin", "https://evil.example")
+
+	handler(w, r, nil)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("Access-Control-Allow-Origin = %q, want unset for a non-allow-listed origin", got)
+	}
+}
+
+func TestCORSEchoesAllowedOrigin(t *testing.T) {
+	handler := CORS(CORSConfig{AllowedOrigins: []string{"https://allowed.example"}})(
+		func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {},
+	)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+	r.Header.Set("Origin", "https://allowed.example")
+
+	handler(w, r, nil)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://allowed.example" {
+		t.Fatalf("Access-Control-Allow-Origin = %q, want https://allowed.example", got)
+	}
+}
+
+func TestCORSSetsAllowCredentialsOnlyWhenConfigured(t *testing.T) {
+	handler := CORS(CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true})(
+		func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {},
+	)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+	r.Header.Set("Origin", "https://any.example")
+
+	handler(w, r, nil)
+
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Fatalf("Access-Control-Allow-Credentials = %q, want true", got)
+	}
+}
+
+func TestCORSSetsMethodsAndHeadersWhenConfigured(t *testing.T) {
+	handler := CORS(CORSConfig{
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Content-Type"},
+	})(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodOptions, "/foo", nil)
+
+	handler(w, r, nil)
+
+	if got := w.Header().Get("Access-Control-Allow-Methods"); got != "GET, POST" {
+		t.Fatalf("Access-Control-Allow-Methods = %q, want %q", got, "GET, POST")
+	}
+	if got := w.Header().Get("Access-Control-Allow-Headers"); got != "Content-Type" {
+		t.Fatalf("Access-Control-Allow-Headers = %q, want Content-Type", got)
+	}
+}
+
+func TestOriginAllowed(t *testing.T) {
+	if !originAllowed([]string{"*"}, "https://anything.example") {
+		t.Fatal("expected a wildcard entry to allow any origin")
+	}
+	if !originAllowed([]string{"https://allowed.example"}, "https://allowed.example") {
+		t.Fatal("expected an exact match to be allowed")
+	}
+	if originAllowed([]string{"https://allowed.example"}, "https://other.example") {
+		t.Fatal("expected a non-matching origin to be rejected")
+	}
+}
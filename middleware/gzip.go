@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/fernandezvara/rest"
+	"github.com/julienschmidt/httprouter"
+)
+
+// GZip compresses the response body when the client advertises support for
+// it via the Accept-Encoding header
+func GZip(next rest.APIHandler) rest.APIHandler {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			next(w, r, ps)
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+
+		next(&gzipResponseWriter{ResponseWriter: w, writer: gz}, r, ps)
+
+	}
+}
+
+// gzipResponseWriter overrides Write to send the body through a gzip.Writer
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	writer io.Writer
+}
+
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	return w.writer.Write(p)
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestGZipCompressesWhenAcceptEncodingAdvertisesIt(t *testing.T) {
+	handler := GZip(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		w.Write([]byte("hello world"))
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+
+	handler(w, r, nil)
+
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+
+	gz, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	defer gz.Close()
+
+	body, err := ioutil.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("reading gzip body: %v", err)
+	}
+	if string(body) != "hello world" {
+		t.Fatalf("body = %q, want %q", body, "hello world")
+	}
+}
+
+func TestGZipPassesThroughWithoutAcceptEncoding(t *testing.T) {
+	handler := GZip(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		w.Write([]byte("hello world"))
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+	handler(w, r, nil)
+
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Fatalf("Content-Encoding = %q, want unset", got)
+	}
+	if w.Body.String() != "hello world" {
+		t.Fatalf("body = %q, want %q", w.Body.String(), "hello world")
+	}
+}
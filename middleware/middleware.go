@@ -0,0 +1,4 @@
+// Package middleware provides a set of ready-made rest.Middleware
+// implementations that can be registered globally through REST.Use or
+// per-route via APIEndpoint.Middlewares.
+package middleware
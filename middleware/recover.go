@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"github.com/fernandezvara/rest"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// Recover turns a panic inside the wrapped handler into a 500 response
+// instead of crashing the server, logging the stack trace through logger
+func Recover(logger *rest.Logging) rest.Middleware {
+	return func(next rest.APIHandler) rest.APIHandler {
+		return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+			defer func() {
+				if rec := recover(); rec != nil {
+					logger.With(r.Context()).Error("panic recovered",
+						zap.Any("panic", rec),
+						zap.ByteString("stack", debug.Stack()),
+					)
+					rest.ErrorResponse(w, r, http.StatusInternalServerError, "")
+				}
+			}()
+
+			next(w, r, ps)
+
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fernandezvara/rest"
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestRecoverTurnsPanicInto500(t *testing.T) {
+	logger, err := rest.NewLogging(nil, nil, false)
+	if err != nil {
+		t.Fatalf("NewLogging: %v", err)
+	}
+
+	handler := Recover(logger)(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+	handler(w, r, nil)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestRecoverLeavesNonPanickingHandlerUntouched(t *testing.T) {
+	logger, err := rest.NewLogging(nil, nil, false)
+	if err != nil {
+		t.Fatalf("NewLogging: %v", err)
+	}
+
+	called := false
+	handler := Recover(logger)(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		called = true
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+	handler(w, r, nil)
+
+	if !called {
+		t.Fatal("expected the wrapped handler to run")
+	}
+	if w.Code != http.StatusTeapot {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusTeapot)
+	}
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/fernandezvara/rest"
+	"github.com/julienschmidt/httprouter"
+)
+
+// Timeout aborts the wrapped handler with a 504 if it has not completed
+// within d. The handler still runs to completion in the background, so it
+// must itself honour r.Context() cancellation to release resources promptly.
+// Its writes are buffered until it finishes, so it never touches the real
+// http.ResponseWriter concurrently with the 504 written on timeout, mirroring
+// the approach taken by net/http.TimeoutHandler. Timeout runs next in its own
+// goroutine, so it also recovers a panic there itself and turns it into a
+// 500, rather than relying on Recover being registered closer to the handler.
+func Timeout(d time.Duration) rest.Middleware {
+	return func(next rest.APIHandler) rest.APIHandler {
+		return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			r = r.WithContext(ctx)
+
+			tw := &timeoutWriter{ResponseWriter: w, header: make(http.Header)}
+
+			done := make(chan struct{})
+			go func() {
+				defer func() {
+					if rec := recover(); rec != nil {
+						tw.mu.Lock()
+						wrote := tw.wroteHeader
+						tw.mu.Unlock()
+						if !wrote {
+							rest.ErrorResponse(tw, r, http.StatusInternalServerError, "")
+						}
+					}
+					close(done)
+				}()
+				next(tw, r, ps)
+			}()
+
+			select {
+			case <-done:
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				if !tw.wroteHeader {
+					tw.writeHeaderLocked(http.StatusOK)
+				}
+				dst := tw.ResponseWriter.Header()
+				for k, v := range tw.header {
+					dst[k] = v
+				}
+				tw.ResponseWriter.WriteHeader(tw.status)
+				tw.ResponseWriter.Write(tw.body.Bytes())
+			case <-ctx.Done():
+				tw.mu.Lock()
+				tw.timedOut = true
+				tw.mu.Unlock()
+				rest.ErrorResponse(w, r, http.StatusGatewayTimeout, "request timed out")
+			}
+
+		}
+	}
+}
+
+// timeoutWriter buffers the handler's response so it can be discarded if the
+// timeout wins the race, instead of being written concurrently with the 504
+type timeoutWriter struct {
+	http.ResponseWriter
+
+	mu          sync.Mutex
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return len(p), nil
+	}
+	if !tw.wroteHeader {
+		tw.writeHeaderLocked(http.StatusOK)
+	}
+	return tw.body.Write(p)
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return
+	}
+	tw.writeHeaderLocked(status)
+}
+
+// writeHeaderLocked records status on tw (the caller's headers are already
+// collected in tw.header), without touching the real http.ResponseWriter:
+// only the <-done branch in Timeout
+// is allowed to write them through, once it is certain the deadline did not
+// win the race.
+func (tw *timeoutWriter) writeHeaderLocked(status int) {
+	if tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	tw.status = status
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestTimeoutLetsFastHandlerThrough(t *testing.T) {
+	handler := Timeout(50 * time.Millisecond)(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+	handler(w, r, nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if w.Body.String() != "ok" {
+		t.Fatalf("body = %q, want %q", w.Body.String(), "ok")
+	}
+}
+
+func TestTimeoutAbortsSlowHandlerWith504(t *testing.T) {
+	released := make(chan struct{})
+	handler := Timeout(10 * time.Millisecond)(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		<-r.Context().Done()
+		close(released)
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+	handler(w, r, nil)
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusGatewayTimeout)
+	}
+
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		t.Fatal("expected the handler's context to be cancelled once the timeout fired")
+	}
+}
+
+func TestTimeoutDiscardsAHandlerWriteThatLosesTheRace(t *testing.T) {
+	unblock := make(chan struct{})
+	handler := Timeout(10 * time.Millisecond)(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("late"))
+		<-unblock
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+	handler(w, r, nil)
+	close(unblock)
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("status = %d, want %d (the handler's 200 must not reach the real ResponseWriter)", w.Code, http.StatusGatewayTimeout)
+	}
+	if strings.Contains(w.Body.String(), "late") {
+		t.Fatalf("body = %q, want the handler's buffered write discarded, not appended to the 504", w.Body.String())
+	}
+}
+
+func TestTimeoutRecoversPanicInItsOwnGoroutine(t *testing.T) {
+	handler := Timeout(50 * time.Millisecond)(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+	handler(w, r, nil)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
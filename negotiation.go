@@ -0,0 +1,172 @@
+package rest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fernandezvara/rest/codec"
+)
+
+var encoderFactories = map[string]func(io.Writer) codec.Encoder{}
+var decoderFactories = map[string]func(io.Reader) codec.Decoder{}
+
+func init() {
+	RegisterEncoder("application/json", func(w io.Writer) codec.Encoder { return codec.NewJSONEncoder(w) })
+	RegisterEncoder("application/x-msgpack", func(w io.Writer) codec.Encoder { return codec.NewMessagePackEncoder(w) })
+	RegisterEncoder("application/yaml", func(w io.Writer) codec.Encoder { return codec.NewYAMLEncoder(w) })
+	RegisterEncoder("application/x-protobuf", func(w io.Writer) codec.Encoder { return codec.NewProtobufEncoder(w) })
+
+	RegisterDecoder("application/json", func(r io.Reader) codec.Decoder { return codec.NewJSONDecoder(r) })
+	RegisterDecoder("application/x-msgpack", func(r io.Reader) codec.Decoder { return codec.NewMessagePackDecoder(r) })
+	RegisterDecoder("application/yaml", func(r io.Reader) codec.Decoder { return codec.NewYAMLDecoder(r) })
+	RegisterDecoder("application/x-protobuf", func(r io.Reader) codec.Decoder { return codec.NewProtobufDecoder(r) })
+}
+
+// RegisterEncoder registers factory as the Encoder used by Negotiate to
+// produce responses of the given mime type
+func RegisterEncoder(mimeType string, factory func(io.Writer) codec.Encoder) {
+	encoderFactories[mimeType] = factory
+}
+
+// RegisterDecoder registers factory as the Decoder used by GetFromBody to
+// read request bodies of the given mime type
+func RegisterDecoder(mimeType string, factory func(io.Reader) codec.Decoder) {
+	decoderFactories[mimeType] = factory
+}
+
+// Negotiate is like Response but picks the response encoder from the
+// request's Accept header instead of always encoding as JSON. If none of
+// the requested types has a registered encoder, it replies 406 with an
+// HTTPErrorStatus listing the supported types.
+func Negotiate(w http.ResponseWriter, r *http.Request, response interface{}, err error, desiredStatus int, location string) int {
+
+	switch err {
+	case nil:
+		mimeType, factory, ok := chooseEncoder(r)
+		if !ok {
+			return notAcceptable(w, r)
+		}
+
+		w.Header().Set("Content-Type", mimeType)
+		if location != "" {
+			w.Header().Set("Location", location)
+		}
+		w.WriteHeader(desiredStatus)
+		if response != nil {
+			enc := factory(w)
+			if encErr := enc.Encode(response); encErr != nil {
+				fmt.Printf("Negotiate: %s encoder failed to encode response: type: %T; value: %q\n", mimeType, encErr, encErr)
+			}
+			if closeErr := enc.Close(); closeErr != nil {
+				fmt.Printf("Negotiate: %s encoder failed to close: type: %T; value: %q\n", mimeType, closeErr, closeErr)
+			}
+		}
+		return desiredStatus
+	case ErrConflict:
+		return ErrorResponse(w, r, http.StatusConflict, "")
+	case ErrBadRequest:
+		return ErrorResponse(w, r, http.StatusBadRequest, "")
+	case ErrUnauthorized:
+		return ErrorResponse(w, r, http.StatusUnauthorized, "")
+	}
+	return ErrorResponse(w, r, http.StatusInternalServerError, err.Error())
+
+}
+
+// notAcceptable replies 406, listing the mime types Negotiate can encode
+func notAcceptable(w http.ResponseWriter, r *http.Request) int {
+
+	status := http.StatusNotAcceptable
+
+	errResponse := HTTPErrorStatus{
+		Code:    status,
+		Message: http.StatusText(status),
+		Reason:  "supported types: " + strings.Join(supportedMimeTypes(), ", "),
+	}
+	if id, ok := RequestIDFromContext(r.Context()); ok {
+		errResponse.RequestID = id
+		w.Header().Set(RequestIDHeader, id)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errResponse)
+
+	return status
+
+}
+
+func supportedMimeTypes() []string {
+	types := make([]string, 0, len(encoderFactories))
+	for mimeType := range encoderFactories {
+		types = append(types, mimeType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// acceptEntry is one weighted entry of an Accept header
+type acceptEntry struct {
+	mime string
+	q    float64
+}
+
+func parseAccept(header string) []acceptEntry {
+
+	if header == "" {
+		return []acceptEntry{{mime: "*/*", q: 1}}
+	}
+
+	parts := strings.Split(header, ",")
+	entries := make([]acceptEntry, 0, len(parts))
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		segments := strings.Split(part, ";")
+		entry := acceptEntry{mime: strings.TrimSpace(segments[0]), q: 1}
+
+		for _, segment := range segments[1:] {
+			segment = strings.TrimSpace(segment)
+			if strings.HasPrefix(segment, "q=") {
+				if parsed, err := strconv.ParseFloat(strings.TrimPrefix(segment, "q="), 64); err == nil {
+					entry.q = parsed
+				}
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].q > entries[j].q })
+
+	return entries
+
+}
+
+// chooseEncoder picks the first registered encoder matching r's Accept
+// header, falling back to JSON for "*/*" or a missing header
+func chooseEncoder(r *http.Request) (mimeType string, factory func(io.Writer) codec.Encoder, ok bool) {
+
+	for _, entry := range parseAccept(r.Header.Get("Accept")) {
+		if entry.mime == "*/*" {
+			if f, exists := encoderFactories["application/json"]; exists {
+				return "application/json; charset=utf-8", f, true
+			}
+			continue
+		}
+		if f, exists := encoderFactories[entry.mime]; exists {
+			return entry.mime, f, true
+		}
+	}
+
+	return "", nil, false
+
+}
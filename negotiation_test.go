@@ -0,0 +1,123 @@
+package rest
+
+import (
+	stdjson "encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestChooseEncoderPicksHighestQValue(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/x-msgpack;q=0.5, application/yaml;q=0.9")
+
+	mimeType, factory, ok := chooseEncoder(r)
+	if !ok {
+		t.Fatal("expected a registered encoder to be chosen")
+	}
+	if mimeType != "application/yaml" {
+		t.Fatalf("mimeType = %q, want application/yaml", mimeType)
+	}
+	if factory == nil {
+		t.Fatal("expected a non-nil encoder factory")
+	}
+}
+
+func TestChooseEncoderFallsBackToJSONForWildcard(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "*/*")
+
+	mimeType, _, ok := chooseEncoder(r)
+	if !ok {
+		t.Fatal("expected */* to resolve to the JSON encoder")
+	}
+	if mimeType != "application/json; charset=utf-8" {
+		t.Fatalf("mimeType = %q, want application/json; charset=utf-8", mimeType)
+	}
+}
+
+func TestChooseEncoderDefaultsToJSONWithNoAcceptHeader(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	mimeType, _, ok := chooseEncoder(r)
+	if !ok || mimeType != "application/json; charset=utf-8" {
+		t.Fatalf("mimeType = %q, ok = %v, want application/json; charset=utf-8, true", mimeType, ok)
+	}
+}
+
+func TestChooseEncoderSkipsUnregisteredTypes(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/x-unknown, application/json;q=0.2")
+
+	mimeType, _, ok := chooseEncoder(r)
+	if !ok || mimeType != "application/json" {
+		t.Fatalf("mimeType = %q, ok = %v, want application/json, true", mimeType, ok)
+	}
+}
+
+func TestChooseEncoderRejectsOnlyUnregisteredTypes(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/x-unknown")
+
+	if _, _, ok := chooseEncoder(r); ok {
+		t.Fatal("expected no encoder to match a wholly unregistered Accept header")
+	}
+}
+
+func TestNegotiateRespondsNotAcceptableForUnsupportedAcceptHeader(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/x-unknown")
+	w := httptest.NewRecorder()
+
+	status := Negotiate(w, r, map[string]string{"ok": "true"}, nil, http.StatusOK, "")
+
+	if status != http.StatusNotAcceptable {
+		t.Fatalf("status = %d, want %d", status, http.StatusNotAcceptable)
+	}
+	if w.Code != http.StatusNotAcceptable {
+		t.Fatalf("response code = %d, want %d", w.Code, http.StatusNotAcceptable)
+	}
+
+	var body HTTPErrorStatus
+	if err := stdjson.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Code != http.StatusNotAcceptable {
+		t.Fatalf("body.Code = %d, want %d", body.Code, http.StatusNotAcceptable)
+	}
+}
+
+func TestNegotiateSurvivesAnEncoderThatFailsToEncode(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/x-protobuf")
+	w := httptest.NewRecorder()
+
+	// map[string]string does not implement proto.Message, so
+	// ProtobufEncoder.Encode returns an error instead of writing anything.
+	status := Negotiate(w, r, map[string]string{"ok": "true"}, nil, http.StatusOK, "")
+
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", status, http.StatusOK)
+	}
+	if w.Body.Len() != 0 {
+		t.Fatalf("body = %q, want empty since Encode never wrote anything", w.Body.String())
+	}
+}
+
+func TestNegotiateEncodesWithChosenMimeType(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+
+	status := Negotiate(w, r, map[string]string{"ok": "true"}, nil, http.StatusCreated, "")
+
+	if status != http.StatusCreated {
+		t.Fatalf("status = %d, want %d", status, http.StatusCreated)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("Content-Type = %q, want application/json", ct)
+	}
+	if w.Code != http.StatusCreated {
+		t.Fatalf("response code = %d, want %d", w.Code, http.StatusCreated)
+	}
+}
@@ -1,19 +1,30 @@
 package rest
 
 import (
-	"io/ioutil"
 	"net/http"
+	"strings"
 )
 
-// GetFromBody is a simple function to fill an object from the Request
+// GetFromBody fills obj by streaming r.Body through the Decoder registered
+// for r's Content-Type, defaulting to JSON when the header is absent or
+// unrecognized. Streaming avoids buffering the whole body in memory, which
+// matters for large uploads. The protobuf decoder is the exception: it reads
+// the whole body into memory before unmarshalling it.
 func GetFromBody(r *http.Request, obj interface{}) error {
 
 	defer r.Body.Close()
-	objectByte, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		return err
+
+	contentType := r.Header.Get("Content-Type")
+	if idx := strings.Index(contentType, ";"); idx != -1 {
+		contentType = contentType[:idx]
+	}
+	contentType = strings.TrimSpace(contentType)
+
+	factory, ok := decoderFactories[contentType]
+	if !ok {
+		factory = decoderFactories["application/json"]
 	}
 
-	return json.Unmarshal(objectByte, &obj)
+	return factory(r.Body).Decode(obj)
 
 }
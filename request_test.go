@@ -0,0 +1,122 @@
+package rest
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	yaml "gopkg.in/yaml.v2"
+)
+
+type bodyTestValue struct {
+	Name string `json:"name" msgpack:"name" yaml:"name"`
+}
+
+func newBodyRequest(t *testing.T, contentType string, body []byte) *http.Request {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	return r
+}
+
+func TestGetFromBodyDecodesJSON(t *testing.T) {
+	r := newBodyRequest(t, "application/json", []byte(`{"name":"ada"}`))
+
+	var out bodyTestValue
+	if err := GetFromBody(r, &out); err != nil {
+		t.Fatalf("GetFromBody: %v", err)
+	}
+	if out.Name != "ada" {
+		t.Fatalf("Name = %q, want ada", out.Name)
+	}
+}
+
+func TestGetFromBodyStripsContentTypeParameters(t *testing.T) {
+	r := newBodyRequest(t, "application/json; charset=utf-8", []byte(`{"name":"grace"}`))
+
+	var out bodyTestValue
+	if err := GetFromBody(r, &out); err != nil {
+		t.Fatalf("GetFromBody: %v", err)
+	}
+	if out.Name != "grace" {
+		t.Fatalf("Name = %q, want grace", out.Name)
+	}
+}
+
+func TestGetFromBodyDefaultsToJSONWithoutContentType(t *testing.T) {
+	r := newBodyRequest(t, "", []byte(`{"name":"alan"}`))
+
+	var out bodyTestValue
+	if err := GetFromBody(r, &out); err != nil {
+		t.Fatalf("GetFromBody: %v", err)
+	}
+	if out.Name != "alan" {
+		t.Fatalf("Name = %q, want alan", out.Name)
+	}
+}
+
+func TestGetFromBodyDefaultsToJSONForUnknownContentType(t *testing.T) {
+	r := newBodyRequest(t, "application/x-unknown", []byte(`{"name":"hopper"}`))
+
+	var out bodyTestValue
+	if err := GetFromBody(r, &out); err != nil {
+		t.Fatalf("GetFromBody: %v", err)
+	}
+	if out.Name != "hopper" {
+		t.Fatalf("Name = %q, want hopper", out.Name)
+	}
+}
+
+func TestGetFromBodyDecodesMessagePack(t *testing.T) {
+	body, err := msgpack.Marshal(bodyTestValue{Name: "turing"})
+	if err != nil {
+		t.Fatalf("msgpack.Marshal: %v", err)
+	}
+	r := newBodyRequest(t, "application/x-msgpack", body)
+
+	var out bodyTestValue
+	if err := GetFromBody(r, &out); err != nil {
+		t.Fatalf("GetFromBody: %v", err)
+	}
+	if out.Name != "turing" {
+		t.Fatalf("Name = %q, want turing", out.Name)
+	}
+}
+
+func TestGetFromBodyDecodesYAML(t *testing.T) {
+	body, err := yaml.Marshal(bodyTestValue{Name: "lovelace"})
+	if err != nil {
+		t.Fatalf("yaml.Marshal: %v", err)
+	}
+	r := newBodyRequest(t, "application/yaml", body)
+
+	var out bodyTestValue
+	if err := GetFromBody(r, &out); err != nil {
+		t.Fatalf("GetFromBody: %v", err)
+	}
+	if out.Name != "lovelace" {
+		t.Fatalf("Name = %q, want lovelace", out.Name)
+	}
+}
+
+func TestGetFromBodyDecodesProtobuf(t *testing.T) {
+	body, err := proto.Marshal(wrapperspb.String("hamilton"))
+	if err != nil {
+		t.Fatalf("proto.Marshal: %v", err)
+	}
+	r := newBodyRequest(t, "application/x-protobuf", body)
+
+	out := &wrapperspb.StringValue{}
+	if err := GetFromBody(r, out); err != nil {
+		t.Fatalf("GetFromBody: %v", err)
+	}
+	if out.Value != "hamilton" {
+		t.Fatalf("Value = %q, want hamilton", out.Value)
+	}
+}
@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// RequestIDHeader is the HTTP header used to propagate the request ID both
+// on incoming and outgoing requests
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the package-private context key used to store the request ID
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the request ID stored in ctx, if any
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok
+}
+
+// WithRequestID returns a copy of ctx carrying the given request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// NewRequestID returns a random UUIDv4, used when a request does not carry
+// its own request ID
+func NewRequestID() string {
+	return generateRequestID()
+}
+
+// generateRequestID returns a random UUIDv4 used when the caller did not
+// supply its own request ID
+func generateRequestID() string {
+
+	var b [16]byte
+
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+
+}
+
+// NewOutboundRequest builds an *http.Request that carries the request ID present
+// in ctx (if any) on the RequestIDHeader, so downstream services can correlate
+// the call with the request that originated it
+func NewOutboundRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if id, ok := RequestIDFromContext(ctx); ok {
+		req.Header.Set(RequestIDHeader, id)
+	}
+
+	return req, nil
+
+}
@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"testing"
+)
+
+func TestWithRequestIDRoundTripsThroughContext(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "req-123")
+
+	id, ok := RequestIDFromContext(ctx)
+	if !ok {
+		t.Fatal("expected a request ID to be present in the context")
+	}
+	if id != "req-123" {
+		t.Fatalf("id = %q, want req-123", id)
+	}
+}
+
+func TestRequestIDFromContextMissing(t *testing.T) {
+	if _, ok := RequestIDFromContext(context.Background()); ok {
+		t.Fatal("expected no request ID in a plain context")
+	}
+}
+
+func TestNewOutboundRequestSetsHeaderFromContext(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "req-abc")
+
+	req, err := NewOutboundRequest(ctx, http.MethodGet, "http://example.com", nil)
+	if err != nil {
+		t.Fatalf("NewOutboundRequest: %v", err)
+	}
+
+	if got := req.Header.Get(RequestIDHeader); got != "req-abc" {
+		t.Fatalf("%s header = %q, want req-abc", RequestIDHeader, got)
+	}
+}
+
+func TestNewOutboundRequestLeavesHeaderUnsetWithoutRequestID(t *testing.T) {
+	req, err := NewOutboundRequest(context.Background(), http.MethodGet, "http://example.com", nil)
+	if err != nil {
+		t.Fatalf("NewOutboundRequest: %v", err)
+	}
+
+	if got := req.Header.Get(RequestIDHeader); got != "" {
+		t.Fatalf("%s header = %q, want unset", RequestIDHeader, got)
+	}
+}
+
+var uuidv4Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestGenerateRequestIDShapeAndUniqueness(t *testing.T) {
+	first := generateRequestID()
+	second := generateRequestID()
+
+	if !uuidv4Pattern.MatchString(first) {
+		t.Fatalf("generateRequestID() = %q, does not match UUIDv4 shape", first)
+	}
+	if !uuidv4Pattern.MatchString(second) {
+		t.Fatalf("generateRequestID() = %q, does not match UUIDv4 shape", second)
+	}
+	if first == second {
+		t.Fatal("expected two successive calls to generateRequestID to produce distinct IDs")
+	}
+}
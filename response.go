@@ -10,12 +10,12 @@ import (
 var json = jsoniter.ConfigCompatibleWithStandardLibrary
 
 // ResponseErr is the Response where a straight error cannot be assigned. Simplified for if err != nil
-func ResponseErr(w http.ResponseWriter, err error) int {
-	return Response(w, nil, err, http.StatusOK, "")
+func ResponseErr(w http.ResponseWriter, r *http.Request, err error) int {
+	return Response(w, r, nil, err, http.StatusOK, "")
 }
 
 // Response is a default func to return data
-func Response(w http.ResponseWriter, response interface{}, err error, desiredStatus int, location string) int {
+func Response(w http.ResponseWriter, r *http.Request, response interface{}, err error, desiredStatus int, location string) int {
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.Header().Set("Accept", "application/json")
@@ -31,67 +31,73 @@ func Response(w http.ResponseWriter, response interface{}, err error, desiredSta
 		}
 		return desiredStatus
 	case ErrConflict:
-		return ErrorResponse(w, http.StatusConflict, "")
+		return ErrorResponse(w, r, http.StatusConflict, "")
 	case ErrBadRequest:
-		return ErrorResponse(w, http.StatusBadRequest, "")
+		return ErrorResponse(w, r, http.StatusBadRequest, "")
 	case ErrUnauthorized:
-		return ErrorResponse(w, http.StatusUnauthorized, "")
+		return ErrorResponse(w, r, http.StatusUnauthorized, "")
 	}
 	fmt.Printf("Unknown err: type: %T; value: %q\n", err, err)
-	return ErrorResponse(w, http.StatusInternalServerError, err.Error())
+	return ErrorResponse(w, r, http.StatusInternalServerError, err.Error())
 }
 
 // NotFound is a generic 404 response that will be returned if the router cannot
 // match a route
 func NotFound(w http.ResponseWriter, r *http.Request) {
-	ErrorResponse(w, http.StatusNotFound, "")
+	ErrorResponse(w, r, http.StatusNotFound, "")
 }
 
 // NotAllowed is a generic 405 response that will be returned if the router can match the method
 func NotAllowed(w http.ResponseWriter, r *http.Request) {
-	ErrorResponse(w, http.StatusMethodNotAllowed, "")
+	ErrorResponse(w, r, http.StatusMethodNotAllowed, "")
 }
 
 // BadRequest is a generic 400 response
 func BadRequest(w http.ResponseWriter, r *http.Request, reason string) {
-	ErrorResponse(w, http.StatusBadRequest, reason)
+	ErrorResponse(w, r, http.StatusBadRequest, reason)
 }
 
 // BadRequestValidation is a generic 400 response describing the validation errors if any
 func BadRequestValidation(w http.ResponseWriter, r *http.Request, reason string, validationErrors map[string]string) {
-	var status int = http.StatusBadRequest
-	w.WriteHeader(status)
-	var errResponse HTTPErrorStatus
-	errResponse.Code = status
-	errResponse.Message = http.StatusText(status)
-	if reason != "" {
-		errResponse.Reason = reason
-	}
-	if len(validationErrors) > 0 {
-		errResponse.ValidationErrors = validationErrors
-	}
-	json.NewEncoder(w).Encode(errResponse)
+	errorResponse(w, r, http.StatusBadRequest, reason, validationErrors)
 }
 
 // Forbidden is a generic 403 response
 func Forbidden(w http.ResponseWriter, r *http.Request, reason string) {
-	ErrorResponse(w, http.StatusForbidden, reason)
+	ErrorResponse(w, r, http.StatusForbidden, reason)
 }
 
 // Unauthorized is a generic 401 response
 func Unauthorized(w http.ResponseWriter, r *http.Request) {
-	ErrorResponse(w, http.StatusUnauthorized, "")
+	ErrorResponse(w, r, http.StatusUnauthorized, "")
 }
 
-// ErrorResponse returns a formatted error
-func ErrorResponse(w http.ResponseWriter, status int, reason string) int {
-	w.WriteHeader(status)
+// ErrorResponse returns a formatted error, tagging it with the request ID
+// carried by r's context (if any) so it can be correlated with the access log
+func ErrorResponse(w http.ResponseWriter, r *http.Request, status int, reason string) int {
+	return errorResponse(w, r, status, reason, nil)
+}
+
+// errorResponse builds and writes an HTTPErrorStatus body for status, tagging
+// it with the request ID carried by r's context (if any) on both the body
+// and the RequestIDHeader, before the status line is written
+func errorResponse(w http.ResponseWriter, r *http.Request, status int, reason string, validationErrors map[string]string) int {
 	var err HTTPErrorStatus
 	err.Code = status
 	err.Message = http.StatusText(status)
 	if reason != "" {
 		err.Reason = reason
 	}
+	if len(validationErrors) > 0 {
+		err.ValidationErrors = validationErrors
+	}
+	if r != nil {
+		if id, ok := RequestIDFromContext(r.Context()); ok {
+			err.RequestID = id
+			w.Header().Set(RequestIDHeader, id)
+		}
+	}
+	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(err)
 	return status
 }
@@ -1,11 +1,13 @@
 package rest
 
 import (
+	"context"
 	"net/http"
+	"regexp"
+	"sync/atomic"
 	"time"
 
 	"github.com/julienschmidt/httprouter"
-	"go.uber.org/zap"
 )
 
 // REST holds all logic for the API defined
@@ -20,6 +22,27 @@ type REST struct {
 	tlsCACert         []byte
 	tls               bool
 	requireClientCert bool
+	middlewares       []Middleware
+	corsMiddlewares   []Middleware
+	corsConfigured    bool
+	accessLogger      AccessLogger
+
+	maxInFlight    int
+	maxLongRunning int
+	longRunningRE  *regexp.Regexp
+	inFlightSem    chan struct{}
+	longRunningSem chan struct{}
+	stats          limitStats
+
+	tlsCertificate atomic.Value // holds *tls.Certificate, set by ReloadTLS
+	tlsCAPool      atomic.Value // holds *x509.CertPool, set by ReloadTLS
+	clientCertPins atomic.Value // holds map[string]struct{}, set by SetClientCertPins
+
+	ready           int32 // 1 once serving, 0 from ShutdownContext onwards
+	shutdownTimeout time.Duration
+	shutdownHooks   []func(context.Context) error
+	healthLivePath  string
+	healthReadyPath string
 }
 
 // New returns a pointer to a REST struct that holds the interactions for the API
@@ -36,32 +59,36 @@ func New(ipPort string, tlsCertificate, tlsKey, tlsCACert []byte, requireClientC
 	rr.tlsCACert = tlsCACert
 	rr.logger = logger
 	rr.requireClientCert = requireClientCert
+	rr.accessLogger = logger
+	rr.ready = 1
 
 	return &rr, err
 
 }
 
-func (rr *REST) log(isErr bool, msg, method, route, uri, remote string, httpErrorCode, size int, duration time.Duration) {
-
-	if isErr {
-		rr.logger.Error("rest", msg,
-			zap.String("method", method),
-			zap.String("uri", uri),
-			zap.String("remote", remote),
-			zap.Int("code", httpErrorCode),
-			zap.Int("size", size),
-			zap.Duration("duration", duration),
-		)
-		return
-	}
+// SetAccessLogger replaces the AccessLogger used by SetupRouter's wrapper to
+// emit one AccessEntry per handled request. It defaults to the *Logging
+// passed to New.
+func (rr *REST) SetAccessLogger(al AccessLogger) {
+	rr.accessLogger = al
+}
 
-	rr.logger.Info("rest", msg,
-		zap.String("method", method),
-		zap.String("uri", uri),
-		zap.String("remote", remote),
-		zap.Int("code", httpErrorCode),
-		zap.Int("size", size),
-		zap.Duration("duration", duration),
-	)
+// Use registers middleware that is applied, in order, to every route added
+// through a later call to SetupRouter
+func (rr *REST) Use(mw ...Middleware) {
+	rr.middlewares = append(rr.middlewares, mw...)
+}
 
+// UseCORS registers mw as global middleware, exactly like Use, and also
+// marks CORS as externally configured: SetupRouter stops writing its
+// hardcoded wildcard CORS headers and applies mw, on its own, to OPTIONS
+// preflight requests. Preflight deliberately runs only the middleware
+// registered through UseCORS rather than the full global stack, so
+// auth-class middleware registered through Use does not reject the
+// credential-less preflight request browsers send ahead of it. Register a
+// CORS policy (e.g. middleware.CORS) with UseCORS instead of Use.
+func (rr *REST) UseCORS(mw Middleware) {
+	rr.middlewares = append(rr.middlewares, mw)
+	rr.corsMiddlewares = append(rr.corsMiddlewares, mw)
+	rr.corsConfigured = true
 }
@@ -3,7 +3,6 @@ package rest
 import (
 	"context"
 	"crypto/tls"
-	"crypto/x509"
 	"fmt"
 	"log"
 	"net/http"
@@ -37,10 +36,13 @@ func (rr *REST) SetupRouter(routes map[string]map[string]APIEndpoint) {
 	for method, mappings := range routes {
 		for route, endpoint := range mappings {
 
-			localMethod := method             // ensure it will be logged
-			localRoute := route               // ensure it will be logged
-			localFunction := endpoint.Handler // function
-			localMatcher := endpoint.Matcher  // ensure naming compliance (if defined)
+			localMethod := method            // ensure it will be logged
+			localRoute := route              // ensure it will be logged
+			localMatcher := endpoint.Matcher // ensure naming compliance (if defined)
+
+			// wrap the endpoint handler once with the global and per-route
+			// middleware stacks, so composition cost is paid at setup time
+			localFunction := chainMiddlewares(endpoint.Handler, rr.middlewares, endpoint.Middlewares)
 
 			// wrapper will handle all common logic as:
 			//  - instrumentation
@@ -50,6 +52,23 @@ func (rr *REST) SetupRouter(routes map[string]map[string]APIEndpoint) {
 
 			wrapper = func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 				now := time.Now()
+
+				requestID := r.Header.Get(RequestIDHeader)
+				if requestID == "" {
+					requestID = generateRequestID()
+				}
+				r.Header.Set(RequestIDHeader, requestID)
+				r = r.WithContext(WithRequestID(r.Context(), requestID))
+				w.Header().Set(RequestIDHeader, requestID)
+
+				release, rejected := rr.acquire(localMethod, r.URL.Path)
+				if rejected {
+					rr.rejectTooManyRequests(w, r, localMethod, localRoute)
+					rr.emitAccessLog(r, requestID, http.StatusTooManyRequests, 0, time.Since(now))
+					return
+				}
+				defer release()
+
 				if rr.tls {
 					w.Header().Add("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
 				}
@@ -58,13 +77,13 @@ func (rr *REST) SetupRouter(routes map[string]map[string]APIEndpoint) {
 					rr.writeHeaders(w, r, localRoute)
 					localFunction(ww, r, ps)
 
-					rr.log(false, "hit", localMethod, localRoute, r.RequestURI, r.RemoteAddr, ww.Status(), ww.Size(), time.Since(now))
+					rr.emitAccessLog(r, requestID, ww.Status(), ww.Size(), time.Since(now))
 					return
 				}
 
 				BadRequest(w, r, "route does not match")
 
-				rr.log(true, "hit", localMethod, localRoute, r.RequestURI, r.RemoteAddr, http.StatusBadRequest, 0, time.Since(now))
+				rr.emitAccessLog(r, requestID, http.StatusBadRequest, 0, time.Since(now))
 
 			}
 
@@ -79,7 +98,14 @@ func (rr *REST) SetupRouter(routes map[string]map[string]APIEndpoint) {
 		}
 	}
 
-	// OPTIONS routes
+	// OPTIONS routes are answered by the middleware registered through
+	// UseCORS wrapped around a terminal 200 OK, not the full global stack:
+	// auth-class middleware registered through Use must not see (and reject)
+	// the credential-less preflight request browsers send ahead of it
+	optionsHandler := chainMiddlewares(func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		w.WriteHeader(http.StatusOK)
+	}, rr.corsMiddlewares)
+
 	for route := range rr.optionsMappings {
 
 		localRoute := route // ensure it will be logged
@@ -87,15 +113,23 @@ func (rr *REST) SetupRouter(routes map[string]map[string]APIEndpoint) {
 		wrapper := func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 
 			now := time.Now()
+
+			requestID := r.Header.Get(RequestIDHeader)
+			if requestID == "" {
+				requestID = generateRequestID()
+			}
+			r = r.WithContext(WithRequestID(r.Context(), requestID))
+			w.Header().Set(RequestIDHeader, requestID)
+
 			if rr.tls {
 				w.Header().Add("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
 			}
 
 			ww := NewLogResponseWriter(w)
 			rr.writeHeaders(ww, r, localRoute)
-			ww.WriteHeader(http.StatusOK)
+			optionsHandler(ww, r, ps)
 
-			rr.log(false, "hit", localMethod, localRoute, r.RequestURI, r.RemoteAddr, ww.Status(), ww.Size(), time.Since(now))
+			rr.emitAccessLog(r, requestID, ww.Status(), ww.Size(), time.Since(now))
 
 		}
 
@@ -110,15 +144,34 @@ func (rr *REST) SetupRouter(routes map[string]map[string]APIEndpoint) {
 	// ensure not found and not allowed handlers are logged also
 	router.NotFound = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		now := time.Now()
-		ErrorResponse(w, http.StatusNotFound, "")
-		rr.log(false, "hit", r.Method, r.RequestURI, r.RequestURI, r.RemoteAddr, http.StatusNotFound, 0, time.Since(now))
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = generateRequestID()
+		}
+		r = r.WithContext(WithRequestID(r.Context(), requestID))
+		ErrorResponse(w, r, http.StatusNotFound, "")
+		rr.emitAccessLog(r, requestID, http.StatusNotFound, 0, time.Since(now))
 	})
 	router.MethodNotAllowed = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		now := time.Now()
-		ErrorResponse(w, http.StatusMethodNotAllowed, "")
-		rr.log(false, "hit", r.Method, r.RequestURI, r.RequestURI, r.RemoteAddr, http.StatusMethodNotAllowed, 0, time.Since(now))
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = generateRequestID()
+		}
+		r = r.WithContext(WithRequestID(r.Context(), requestID))
+		ErrorResponse(w, r, http.StatusMethodNotAllowed, "")
+		rr.emitAccessLog(r, requestID, http.StatusMethodNotAllowed, 0, time.Since(now))
 	})
 
+	if rr.healthLivePath != "" {
+		router.GET(rr.healthLivePath, rr.handleLive)
+		rr.logger.Info("rest", "HTTP route added", zap.String("method", "GET"), zap.String("route", rr.healthLivePath))
+	}
+	if rr.healthReadyPath != "" {
+		router.GET(rr.healthReadyPath, rr.handleReady)
+		rr.logger.Info("rest", "HTTP route added", zap.String("method", "GET"), zap.String("route", rr.healthReadyPath))
+	}
+
 	rr.router = router
 
 }
@@ -152,19 +205,11 @@ func (rr *REST) Start() error {
 	// certificate information? then add TLS configuration
 	if len(rr.tlsCer) > 0 && len(rr.tlsKey) > 0 && len(rr.tlsCACert) > 0 {
 
-		var (
-			tlsConfig   tls.Config
-			certificate tls.Certificate
-		)
+		var tlsConfig tls.Config
 
-		caCertPool := x509.NewCertPool()
-		caCertPool.AppendCertsFromPEM(rr.tlsCACert)
-
-		tlsConfig.ClientCAs = caCertPool
 		if rr.requireClientCert {
 			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
 		}
-		tlsConfig.BuildNameToCertificate()
 
 		rr.tls = true
 		tlsConfig.MinVersion = tls.VersionTLS12
@@ -178,12 +223,27 @@ func (rr *REST) Start() error {
 
 		tlsConfig.NextProtos = []string{"h2", "http/1.1"}
 
-		certificate, err = tls.X509KeyPair(rr.tlsCer, rr.tlsKey)
-		if err != nil {
-			return err
+		// certificate and client CA pool are read from atomic storage on every
+		// handshake, so ReloadTLS/WatchTLSFiles can rotate them with zero downtime
+		tlsConfig.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return rr.currentCertificate()
+		}
+		tlsConfig.VerifyPeerCertificate = rr.verifyClientCertPins
+		tlsConfig.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cfg := tlsConfig.Clone()
+			cfg.GetConfigForClient = nil
+			cfg.ClientCAs = rr.currentCAPool()
+			// client cert pins need a client certificate to be solicited to have
+			// any effect, even when the server wasn't configured to require one
+			if cfg.ClientAuth == tls.NoClientCert && rr.hasClientCertPins() {
+				cfg.ClientAuth = tls.RequireAnyClientCert
+			}
+			return cfg, nil
 		}
 
-		tlsConfig.Certificates = []tls.Certificate{certificate}
+		if err = rr.ReloadTLS(rr.tlsCer, rr.tlsKey, rr.tlsCACert); err != nil {
+			return err
+		}
 
 		rr.httpServer.TLSConfig = &tlsConfig
 
@@ -194,9 +254,30 @@ func (rr *REST) Start() error {
 
 }
 
-// Shutdown tells the http server to stop gracefully
+// Shutdown tells the http server to stop gracefully. It is kept for backwards
+// compatibility and is ShutdownContext called with context.Background(), so
+// it also flips the readiness flag and runs the registered OnShutdown hooks,
+// but puts no bound on the drain of in-flight requests.
 func (rr *REST) Shutdown() error {
-	return rr.httpServer.Shutdown(context.Background())
+	return rr.ShutdownContext(context.Background())
+}
+
+// chainMiddlewares composes the global and per-route middleware stacks around
+// handler, global middlewares running outermost, so that each is wrapped
+// exactly once at router setup time instead of on every request
+func chainMiddlewares(handler APIHandler, stacks ...[]Middleware) APIHandler {
+
+	var mws []Middleware
+	for _, stack := range stacks {
+		mws = append(mws, stack...)
+	}
+
+	for i := len(mws) - 1; i >= 0; i-- {
+		handler = mws[i](handler)
+	}
+
+	return handler
+
 }
 
 // match is the local function that verifies
@@ -228,9 +309,11 @@ func match(route string, matcher []string, r *http.Request) bool {
 }
 
 func (rr *REST) writeHeaders(w http.ResponseWriter, r *http.Request, route string) {
-	w.Header().Add("Access-Control-Allow-Origin", "*")
-	w.Header().Add("Access-Control-Allow-Headers", "Origin, X-Requested-With, Content-Type, Accept, X-Session-Token")
-	w.Header().Add("Access-Control-Allow-Methods", fmt.Sprintf("OPTIONS, %s", strings.Join(rr.optionsMappings[route], ", ")))
+	if !rr.corsConfigured {
+		w.Header().Add("Access-Control-Allow-Origin", "*")
+		w.Header().Add("Access-Control-Allow-Headers", "Origin, X-Requested-With, Content-Type, Accept, X-Session-Token")
+		w.Header().Add("Access-Control-Allow-Methods", fmt.Sprintf("OPTIONS, %s", strings.Join(rr.optionsMappings[route], ", ")))
+	}
 	w.Header().Add("X-Content-Type-Options", "nosniff")
 	w.Header().Add("X-Frame-Options", "DENY")
 	w.Header().Add("X-XSS-Protection", "1; mode=block")
@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// recordingMiddleware appends name to order when entering and leaving the
+// handler it wraps, so tests can assert both composition and call order
+func recordingMiddleware(order *[]string, name string) Middleware {
+	return func(next APIHandler) APIHandler {
+		return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+			*order = append(*order, name+":in")
+			next(w, r, ps)
+			*order = append(*order, name+":out")
+		}
+	}
+}
+
+func TestChainMiddlewaresRunsGlobalOutermost(t *testing.T) {
+	var order []string
+
+	handler := func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		order = append(order, "handler")
+	}
+
+	global := []Middleware{recordingMiddleware(&order, "global1"), recordingMiddleware(&order, "global2")}
+	perRoute := []Middleware{recordingMiddleware(&order, "route1")}
+
+	chained := chainMiddlewares(handler, global, perRoute)
+	chained(nil, &http.Request{}, nil)
+
+	want := []string{
+		"global1:in", "global2:in", "route1:in",
+		"handler",
+		"route1:out", "global2:out", "global1:out",
+	}
+
+	if len(order) != len(want) {
+		t.Fatalf("call order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("call order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestChainMiddlewaresWithNoMiddlewareCallsHandlerDirectly(t *testing.T) {
+	called := false
+	handler := func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		called = true
+	}
+
+	chained := chainMiddlewares(handler)
+	chained(nil, &http.Request{}, nil)
+
+	if !called {
+		t.Fatal("expected the handler to run when no middleware stacks are given")
+	}
+}
@@ -0,0 +1,114 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// defaultShutdownTimeout is used by ShutdownGraceful when SetShutdownTimeout
+// has not been called
+const defaultShutdownTimeout = 30 * time.Second
+
+// multiError aggregates the errors returned by ShutdownContext's http server
+// shutdown and its OnShutdown hooks
+type multiError []error
+
+func (m multiError) Error() string {
+	msgs := make([]string, len(m))
+	for i, err := range m {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// SetShutdownTimeout sets the drain timeout used by ShutdownGraceful
+func (rr *REST) SetShutdownTimeout(d time.Duration) {
+	rr.shutdownTimeout = d
+}
+
+// OnShutdown registers a hook invoked, in registration order, once the HTTP
+// server has stopped accepting new connections. Hook errors are aggregated
+// and returned together, so one failing hook does not prevent the others
+// from running.
+func (rr *REST) OnShutdown(hook func(context.Context) error) {
+	rr.shutdownHooks = append(rr.shutdownHooks, hook)
+}
+
+// ShutdownContext marks the instance as not ready, stops the HTTP server
+// using ctx to bound the drain of in-flight requests, and then runs the
+// registered OnShutdown hooks. The hooks run against their own context,
+// bounded by SetShutdownTimeout (or defaultShutdownTimeout when unset),
+// rather than ctx: by the time the drain finishes, ctx may already be past
+// its deadline, which would fail every context-honoring hook outright.
+func (rr *REST) ShutdownContext(ctx context.Context) error {
+
+	atomic.StoreInt32(&rr.ready, 0)
+
+	var errs multiError
+
+	if err := rr.httpServer.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	hookCtx, cancel := context.WithTimeout(context.Background(), rr.shutdownTimeoutOrDefault())
+	defer cancel()
+
+	for _, hook := range rr.shutdownHooks {
+		if err := hook(hookCtx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+
+}
+
+// ShutdownGraceful builds a context bounded by SetShutdownTimeout (or
+// defaultShutdownTimeout when unset) and shuts the instance down with it
+func (rr *REST) ShutdownGraceful() error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), rr.shutdownTimeoutOrDefault())
+	defer cancel()
+
+	return rr.ShutdownContext(ctx)
+
+}
+
+// shutdownTimeoutOrDefault returns the timeout set by SetShutdownTimeout, or
+// defaultShutdownTimeout when none was set
+func (rr *REST) shutdownTimeoutOrDefault() time.Duration {
+	if rr.shutdownTimeout <= 0 {
+		return defaultShutdownTimeout
+	}
+	return rr.shutdownTimeout
+}
+
+// EnableHealthEndpoints registers a /healthz-style liveness route and a
+// /readyz-style readiness route on the next call to SetupRouter. The
+// readiness route returns 503 once shutdown has started, so a load balancer
+// can deregister the instance before in-flight requests finish draining.
+func (rr *REST) EnableHealthEndpoints(live, ready string) {
+	rr.healthLivePath = live
+	rr.healthReadyPath = ready
+}
+
+func (rr *REST) handleLive(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (rr *REST) handleReady(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	if atomic.LoadInt32(&rr.ready) == 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
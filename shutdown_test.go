@@ -0,0 +1,133 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newShutdownTestREST() *REST {
+	rr := &REST{ready: 1}
+	rr.httpServer = &http.Server{}
+	return rr
+}
+
+func TestHandleReadyReflectsReadyFlag(t *testing.T) {
+	rr := newShutdownTestREST()
+
+	w := httptest.NewRecorder()
+	rr.handleReady(w, httptest.NewRequest(http.MethodGet, "/readyz", nil), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d while ready", w.Code, http.StatusOK)
+	}
+
+	atomic.StoreInt32(&rr.ready, 0)
+
+	w = httptest.NewRecorder()
+	rr.handleReady(w, httptest.NewRequest(http.MethodGet, "/readyz", nil), nil)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d once shutdown has started", w.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestShutdownContextClearsReadyFlag(t *testing.T) {
+	rr := newShutdownTestREST()
+
+	if err := rr.ShutdownContext(context.Background()); err != nil {
+		t.Fatalf("ShutdownContext: %v", err)
+	}
+
+	if atomic.LoadInt32(&rr.ready) != 0 {
+		t.Fatal("expected ShutdownContext to clear the ready flag")
+	}
+}
+
+func TestShutdownContextRunsHooksInOrder(t *testing.T) {
+	rr := newShutdownTestREST()
+
+	var order []string
+	rr.OnShutdown(func(context.Context) error {
+		order = append(order, "first")
+		return nil
+	})
+	rr.OnShutdown(func(context.Context) error {
+		order = append(order, "second")
+		return nil
+	})
+
+	if err := rr.ShutdownContext(context.Background()); err != nil {
+		t.Fatalf("ShutdownContext: %v", err)
+	}
+
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Fatalf("hook call order = %v, want [first second]", order)
+	}
+}
+
+func TestShutdownContextAggregatesHookErrors(t *testing.T) {
+	rr := newShutdownTestREST()
+
+	errFirst := errors.New("first hook failed")
+	errSecond := errors.New("second hook failed")
+
+	ran := false
+	rr.OnShutdown(func(context.Context) error { return errFirst })
+	rr.OnShutdown(func(context.Context) error {
+		ran = true
+		return errSecond
+	})
+
+	err := rr.ShutdownContext(context.Background())
+	if err == nil {
+		t.Fatal("expected an aggregated error when hooks fail")
+	}
+	if !ran {
+		t.Fatal("expected the second hook to run even though the first failed")
+	}
+
+	merr, ok := err.(multiError)
+	if !ok || len(merr) != 2 {
+		t.Fatalf("err = %#v, want a multiError with 2 entries", err)
+	}
+}
+
+func TestShutdownContextHooksRunEvenPastDrainDeadline(t *testing.T) {
+	rr := newShutdownTestREST()
+	rr.SetShutdownTimeout(time.Second)
+
+	ran := false
+	rr.OnShutdown(func(ctx context.Context) error {
+		ran = true
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("hook context should not be cancelled, got %v", err)
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	cancel()
+
+	if err := rr.ShutdownContext(ctx); err != nil {
+		t.Fatalf("ShutdownContext: %v", err)
+	}
+	if !ran {
+		t.Fatal("expected the hook to run despite the drain context already being past its deadline")
+	}
+}
+
+func TestShutdownTimeoutOrDefault(t *testing.T) {
+	rr := &REST{}
+
+	if got := rr.shutdownTimeoutOrDefault(); got != defaultShutdownTimeout {
+		t.Fatalf("shutdownTimeoutOrDefault() = %v, want %v when unset", got, defaultShutdownTimeout)
+	}
+
+	rr.SetShutdownTimeout(5 * time.Second)
+	if got := rr.shutdownTimeoutOrDefault(); got != 5*time.Second {
+		t.Fatalf("shutdownTimeoutOrDefault() = %v, want 5s once set", got)
+	}
+}
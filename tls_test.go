@@ -0,0 +1,137 @@
+package rest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// generateTestCert returns a freshly minted, self-signed leaf certificate
+// for exercising verifyClientCertPins
+func generateTestCert(t *testing.T) *x509.Certificate {
+	t.Helper()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+
+	return cert
+}
+
+// spkiPin mirrors the digest verifyClientCertPins computes, for building
+// the pin lists exercised by the tests below
+func spkiPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestVerifyClientCertPinsDisabledByDefault(t *testing.T) {
+	rr := &REST{}
+	cert := generateTestCert(t)
+
+	if err := rr.verifyClientCertPins([][]byte{cert.Raw}, nil); err != nil {
+		t.Fatalf("expected no error when pinning is not configured, got %v", err)
+	}
+}
+
+func TestVerifyClientCertPinsAcceptsPinnedCert(t *testing.T) {
+	rr := &REST{}
+	cert := generateTestCert(t)
+
+	rr.SetClientCertPins([]string{spkiPin(cert)})
+
+	if err := rr.verifyClientCertPins([][]byte{cert.Raw}, nil); err != nil {
+		t.Fatalf("expected pinned certificate to be accepted, got %v", err)
+	}
+}
+
+func TestVerifyClientCertPinsRejectsUnpinnedCert(t *testing.T) {
+	rr := &REST{}
+	pinned := generateTestCert(t)
+	other := generateTestCert(t)
+
+	rr.SetClientCertPins([]string{spkiPin(pinned)})
+
+	if err := rr.verifyClientCertPins([][]byte{other.Raw}, nil); err == nil {
+		t.Fatal("expected unpinned certificate to be rejected")
+	}
+}
+
+func TestVerifyClientCertPinsRejectsMissingCert(t *testing.T) {
+	rr := &REST{}
+	cert := generateTestCert(t)
+
+	rr.SetClientCertPins([]string{spkiPin(cert)})
+
+	if err := rr.verifyClientCertPins(nil, nil); err == nil {
+		t.Fatal("expected a handshake with no client certificate to be rejected")
+	}
+}
+
+func TestSetClientCertPinsIsCaseInsensitive(t *testing.T) {
+	rr := &REST{}
+	cert := generateTestCert(t)
+
+	rr.SetClientCertPins([]string{strings.ToUpper(spkiPin(cert))})
+
+	if err := rr.verifyClientCertPins([][]byte{cert.Raw}, nil); err != nil {
+		t.Fatalf("expected pin comparison to ignore case, got %v", err)
+	}
+}
+
+func TestSetClientCertPinsEmptyDisablesPinning(t *testing.T) {
+	rr := &REST{}
+	cert := generateTestCert(t)
+
+	rr.SetClientCertPins([]string{spkiPin(cert)})
+	rr.SetClientCertPins(nil)
+
+	other := generateTestCert(t)
+	if err := rr.verifyClientCertPins([][]byte{other.Raw}, nil); err != nil {
+		t.Fatalf("expected clearing the pin list to disable pinning, got %v", err)
+	}
+}
+
+func TestHasClientCertPins(t *testing.T) {
+	rr := &REST{}
+
+	if rr.hasClientCertPins() {
+		t.Fatal("expected no pins before SetClientCertPins is called")
+	}
+
+	rr.SetClientCertPins([]string{spkiPin(generateTestCert(t))})
+	if !rr.hasClientCertPins() {
+		t.Fatal("expected pins to be reported once SetClientCertPins is called with hashes")
+	}
+
+	rr.SetClientCertPins(nil)
+	if rr.hasClientCertPins() {
+		t.Fatal("expected clearing the pin list to report no pins")
+	}
+}